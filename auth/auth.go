@@ -0,0 +1,185 @@
+// auth/auth.go
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+
+	"effective-mobile/config"
+)
+
+const defaultJWKSRefreshInterval = 1 * time.Hour
+
+// provider/verifier - переизданы refreshJWKSPeriodically в своей горутине, пока
+// RequireScope читает их на каждый запрос, поэтому оба под atomic.Pointer, а не
+// обычные переменные.
+var (
+	provider atomic.Pointer[oidc.Provider]
+	verifier atomic.Pointer[oidc.IDTokenVerifier]
+)
+
+// Init настраивает OIDC-провайдера и верификатор токенов, которыми
+// пользуется RequireScope, и запускает фоновое периодическое обновление
+// JWKS. Ничего не делает, если AUTH_MODE=disabled (локальная разработка).
+func Init() error {
+	if Disabled() {
+		config.Log.Warn("AUTH_MODE=disabled, пропускаем настройку OIDC - не используйте это в продакшене")
+		return nil
+	}
+
+	issuer := os.Getenv("OIDC_ISSUER")
+	clientID := os.Getenv("OIDC_CLIENT_ID")
+	if issuer == "" || clientID == "" {
+		return fmt.Errorf("OIDC_ISSUER и OIDC_CLIENT_ID должны быть заданы, если AUTH_MODE != disabled")
+	}
+
+	if err := refreshProvider(issuer, clientID); err != nil {
+		return err
+	}
+	go refreshJWKSPeriodically(issuer, clientID)
+	return nil
+}
+
+// refreshProvider перезапрашивает метаданные и JWKS провайдера.
+func refreshProvider(issuer, clientID string) error {
+	p, err := oidc.NewProvider(context.Background(), issuer)
+	if err != nil {
+		return fmt.Errorf("не удалось получить метаданные OIDC-провайдера: %w", err)
+	}
+	provider.Store(p)
+	verifier.Store(p.Verifier(&oidc.Config{ClientID: clientID}))
+	config.Log.Debug("JWKS обновлены")
+	return nil
+}
+
+// refreshJWKSPeriodically переобновляет JWKS раз в OIDC_JWKS_REFRESH_INTERVAL,
+// чтобы ключи ротации провайдера подхватывались без перезапуска сервиса.
+func refreshJWKSPeriodically(issuer, clientID string) {
+	ticker := time.NewTicker(envDuration("OIDC_JWKS_REFRESH_INTERVAL", defaultJWKSRefreshInterval))
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := refreshProvider(issuer, clientID); err != nil {
+			config.Log.Warnf("Не удалось обновить JWKS: %v", err)
+		}
+	}
+}
+
+// Disabled сообщает, выключена ли аутентификация для локальной разработки.
+func Disabled() bool {
+	return os.Getenv("AUTH_MODE") == "disabled"
+}
+
+// tokenClaims - часть claims ID-токена, которая нужна RequireScope.
+type tokenClaims struct {
+	Subject string `json:"sub"`
+	Scope   string `json:"scope"`
+}
+
+type claimsCtxKeyType struct{}
+
+var claimsCtxKey claimsCtxKeyType
+
+// RequireScope возвращает Gin-middleware, которая отклоняет запросы без
+// валидного bearer-токена или без требуемого OAuth2-scope. При успехе
+// сохраняет subject токена в контексте Gin под ключом "subject", чтобы
+// обработчики могли проставить его в created_by/updated_by, а также кладёт
+// полные claims в context.Context запроса, чтобы HasScope была доступна
+// обработчикам с единой точкой входа на несколько операций (см. GraphQL
+// резолверы в graph/schema.resolvers.go, где RequireScope на /graphql
+// проверяет только минимальный scope для всего эндпоинта, а мутации сверх
+// этого требуют people:write через HasScope).
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if Disabled() {
+			c.Next()
+			return
+		}
+
+		rawToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if rawToken == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer token"})
+			return
+		}
+
+		idToken, err := verifier.Load().Verify(c.Request.Context(), rawToken)
+		if err != nil {
+			config.Log.Warnf("Отклонён недействительный OIDC-токен: %v", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid bearer token"})
+			return
+		}
+
+		var claims tokenClaims
+		if err := idToken.Claims(&claims); err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
+			return
+		}
+		if !hasScope(claims.Scope, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing required scope: " + scope})
+			return
+		}
+
+		c.Set("subject", claims.Subject)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), claimsCtxKey, claims))
+		c.Next()
+	}
+}
+
+func hasScope(scopeClaim, required string) bool {
+	for _, s := range strings.Fields(scopeClaim) {
+		if s == required {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope сообщает, несёт ли ctx (запрос, прошедший через RequireScope)
+// требуемый OAuth2-scope. Используется там, где один HTTP-роут обслуживает
+// несколько операций с разными требованиями к правам - в частности, GraphQL
+// резолверами мутаций, которым помимо базового scope самого /graphql
+// эндпоинта нужен ещё people:write. Всегда true при AUTH_MODE=disabled.
+func HasScope(ctx context.Context, scope string) bool {
+	if Disabled() {
+		return true
+	}
+	claims, ok := ctx.Value(claimsCtxKey).(tokenClaims)
+	if !ok {
+		return false
+	}
+	return hasScope(claims.Scope, scope)
+}
+
+// Subject returns the OIDC subject carried on ctx by RequireScope, or nil if
+// AUTH_MODE=disabled or ctx never went through RequireScope. Mirrors
+// handlers.actorSubject (which reads the same subject off gin.Context) for
+// callers, such as the GraphQL resolvers, that only have a context.Context.
+func Subject(ctx context.Context) *string {
+	if Disabled() {
+		return nil
+	}
+	claims, ok := ctx.Value(claimsCtxKey).(tokenClaims)
+	if !ok || claims.Subject == "" {
+		return nil
+	}
+	return &claims.Subject
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(val)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,198 @@
+// cache/cache.go
+package cache
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"effective-mobile/config"
+)
+
+const (
+	defaultPositiveTTL = 24 * time.Hour
+	defaultNegativeTTL = 1 * time.Hour
+	defaultLRUSize     = 10000
+)
+
+// Cache - глобальный экземпляр кэша обогащения, используемый services.EnrichPerson.
+// Инициализируется в InitCache() при старте приложения.
+var Cache EnrichmentCache
+
+// EnrichmentCache - интерфейс кэша результатов обогащения по одному провайдеру
+// (agify/genderize/nationalize). Ключом служит нормализованное имя, значением -
+// сериализованный ответ провайдера. Реализации: in-memory LRU (по умолчанию)
+// и Redis (CACHE_BACKEND=redis).
+type EnrichmentCache interface {
+	// Get возвращает сохранённое значение для key и признак, было ли оно найдено.
+	// found=true вместе с negative=true означает "известно, что провайдер для
+	// этого имени ничего не возвращает" - такой результат тоже не требует
+	// повторного похода во внешний API.
+	Get(key string) (value string, negative bool, found bool)
+	// Set сохраняет value для key на время ttl. Пустой value с negative=true
+	// сохраняет отрицательный результат.
+	Set(key string, value string, negative bool, ttl time.Duration)
+}
+
+var (
+	cacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "enrichment_cache_hits_total",
+		Help: "Number of enrichment cache lookups that returned a cached positive result.",
+	}, []string{"provider"})
+	cacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "enrichment_cache_misses_total",
+		Help: "Number of enrichment cache lookups that found nothing cached.",
+	}, []string{"provider"})
+	cacheNegativeHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "enrichment_cache_negative_hits_total",
+		Help: "Number of enrichment cache lookups that returned a cached negative (empty) result.",
+	}, []string{"provider"})
+)
+
+func init() {
+	prometheus.MustRegister(cacheHits, cacheMisses, cacheNegativeHits)
+}
+
+// Observe обновляет hit/miss/negative-hit метрики для provider по результату
+// вызова Get. Вызывается из services.EnrichPerson, которому известно, какому
+// провайдеру принадлежит запрошенный ключ.
+func Observe(provider string, negative, found bool) {
+	switch {
+	case found && negative:
+		cacheNegativeHits.WithLabelValues(provider).Inc()
+	case found:
+		cacheHits.WithLabelValues(provider).Inc()
+	default:
+		cacheMisses.WithLabelValues(provider).Inc()
+	}
+}
+
+// PositiveTTL и NegativeTTL - время жизни успешных и отрицательных записей
+// в кэше, настраиваемые через переменные окружения.
+func PositiveTTL() time.Duration { return envDuration("ENRICH_CACHE_TTL", defaultPositiveTTL) }
+func NegativeTTL() time.Duration {
+	return envDuration("ENRICH_CACHE_NEGATIVE_TTL", defaultNegativeTTL)
+}
+
+// InitCache выбирает и инициализирует реализацию EnrichmentCache на основе
+// переменной окружения CACHE_BACKEND ("redis" или, по умолчанию, "memory").
+func InitCache() {
+	backend := os.Getenv("CACHE_BACKEND")
+	if backend == "redis" {
+		config.Log.Info("Initializing Redis-backed enrichment cache")
+		Cache = newRedisCache()
+		return
+	}
+	config.Log.Info("Initializing in-memory LRU enrichment cache")
+	Cache = newLRUCache(defaultLRUSize)
+}
+
+// lruCache - дефолтный backend на случай, если Redis не сконфигурирован.
+type lruCache struct {
+	entries *lru.Cache[string, lruEntry]
+}
+
+type lruEntry struct {
+	value    string
+	negative bool
+	expires  time.Time
+}
+
+func newLRUCache(size int) *lruCache {
+	entries, err := lru.New[string, lruEntry](size)
+	if err != nil {
+		// Размер кэша задан константой, поэтому ошибка здесь означает баг в коде.
+		config.Log.Fatalf("Failed to create LRU enrichment cache: %v", err)
+	}
+	return &lruCache{entries: entries}
+}
+
+func (c *lruCache) Get(key string) (string, bool, bool) {
+	entry, ok := c.entries.Get(key)
+	if !ok || time.Now().After(entry.expires) {
+		return "", false, false
+	}
+	return entry.value, entry.negative, true
+}
+
+func (c *lruCache) Set(key string, value string, negative bool, ttl time.Duration) {
+	c.entries.Add(key, lruEntry{value: value, negative: negative, expires: time.Now().Add(ttl)})
+}
+
+// redisCache - опциональный backend, разделяемый между инстансами сервиса.
+type redisCache struct {
+	client *redis.Client
+}
+
+func newRedisCache() *redisCache {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       envInt("REDIS_DB", 0),
+	})
+	return &redisCache{client: client}
+}
+
+// redisNegativeSentinel - значение, которым в Redis помечается отрицательный
+// результат, поскольку value-поле само по себе не различает "пусто" и "не обогащено".
+const redisNegativeSentinel = "\x00negative"
+
+func (c *redisCache) Get(key string) (string, bool, bool) {
+	ctx := context.Background()
+	val, err := c.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, false
+	}
+	if err != nil {
+		config.Log.Warnf("Redis enrichment cache GET failed for %s: %v", key, err)
+		return "", false, false
+	}
+	if val == redisNegativeSentinel {
+		return "", true, true
+	}
+	return val, false, true
+}
+
+func (c *redisCache) Set(key string, value string, negative bool, ttl time.Duration) {
+	ctx := context.Background()
+	stored := value
+	if negative {
+		stored = redisNegativeSentinel
+	}
+	if err := c.client.Set(ctx, key, stored, ttl).Err(); err != nil {
+		config.Log.Warnf("Redis enrichment cache SET failed for %s: %v", key, err)
+	}
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(val)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envInt(name string, fallback int) int {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheMiss(t *testing.T) {
+	c := newLRUCache(8)
+
+	if _, _, found := c.Get("agify:alice"); found {
+		t.Fatalf("expected miss on empty cache")
+	}
+}
+
+func TestLRUCachePositiveHit(t *testing.T) {
+	c := newLRUCache(8)
+
+	c.Set("agify:alice", "42", false, time.Minute)
+
+	value, negative, found := c.Get("agify:alice")
+	if !found {
+		t.Fatalf("expected hit after Set")
+	}
+	if negative {
+		t.Fatalf("expected positive entry, got negative")
+	}
+	if value != "42" {
+		t.Fatalf("expected value %q, got %q", "42", value)
+	}
+}
+
+func TestLRUCacheNegativeHit(t *testing.T) {
+	c := newLRUCache(8)
+
+	c.Set("genderize:xyzzy", "", true, time.Minute)
+
+	value, negative, found := c.Get("genderize:xyzzy")
+	if !found {
+		t.Fatalf("expected hit for negative entry")
+	}
+	if !negative {
+		t.Fatalf("expected negative entry")
+	}
+	if value != "" {
+		t.Fatalf("expected empty value for negative entry, got %q", value)
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := newLRUCache(8)
+
+	c.Set("nationalize:bob", "US", false, -time.Second)
+
+	if _, _, found := c.Get("nationalize:bob"); found {
+		t.Fatalf("expected expired entry to be treated as a miss")
+	}
+}
+
+func TestObserve(t *testing.T) {
+	// Observe must not panic regardless of the found/negative combination -
+	// it only feeds the three cacheHits/cacheMisses/cacheNegativeHits counters.
+	Observe("agify", false, false)
+	Observe("agify", false, true)
+	Observe("agify", true, true)
+}
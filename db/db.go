@@ -9,7 +9,6 @@ import (
 	"gorm.io/gorm"             // Основная библиотека GORM
 
 	"effective-mobile/config" // Импортируем наш логгер (используем effective-mobile, как вы указали)
-	"effective-mobile/models" // Импортируем нашу модель Person (используем effective-mobile, как вы указали)
 )
 
 // DB - это глобальный экземпляр подключения к базе данных GORM.
@@ -41,13 +40,13 @@ func InitDB() {
 	}
 	config.Log.Info("Database connection established") // Информационный лог
 
-	// 4. Выполнение автоматических миграций GORM
-	err = DB.AutoMigrate(&models.Person{})
-	if err != nil {
-		// Если миграции не удалось выполнить, это критично.
-		log.Fatalf("Failed to auto migrate database: %v", err)
+	// 4. Проверка версии схемы БД. Миграции теперь версионированные
+	// (см. db/migrations и `go run . migrate`), а не автоматические,
+	// поэтому сервер отказывается стартовать на рассинхронизированной базе.
+	if err := CheckSchemaVersion(); err != nil {
+		log.Fatalf("Database schema check failed: %v", err)
 	}
-	config.Log.Info("Database migrations completed") // Информационный лог
+	config.Log.Info("Database schema version check passed") // Информационный лог
 }
 
 // CloseDB закрывает соединение с базой данных.
@@ -0,0 +1,140 @@
+// db/migrate.go
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres" // регистрирует драйвер "postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"effective-mobile/config"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// expectedSchemaVersion - номер последней миграции, на которую рассчитан
+// этот бинарник. Обновляйте при добавлении новой migrations/NNNN_*.up.sql.
+const expectedSchemaVersion = 6
+
+// newMigrate строит *migrate.Migrate поверх встроенных SQL-файлов и той же
+// DATABASE_URL, которой пользуется InitDB.
+func newMigrate() (*migrate.Migrate, error) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return nil, errors.New("DATABASE_URL environment variable not set")
+	}
+
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize migrate: %w", err)
+	}
+	return m, nil
+}
+
+// MigrateUp применяет все невыполненные миграции.
+func MigrateUp() error {
+	m, err := newMigrate()
+	if err != nil {
+		return err
+	}
+	defer closeMigrate(m)
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	config.Log.Info("Database migrations applied")
+	return nil
+}
+
+// MigrateDown откатывает все применённые миграции.
+func MigrateDown() error {
+	m, err := newMigrate()
+	if err != nil {
+		return err
+	}
+	defer closeMigrate(m)
+
+	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	config.Log.Info("Database migrations rolled back")
+	return nil
+}
+
+// MigrationStatus печатает текущую версию схемы и её состояние (dirty или нет).
+func MigrationStatus() error {
+	m, err := newMigrate()
+	if err != nil {
+		return err
+	}
+	defer closeMigrate(m)
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		config.Log.Info("Database schema version: none (no migrations applied yet)")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read migration status: %w", err)
+	}
+	config.Log.Infof("Database schema version: %d (dirty=%t, expected=%d)", version, dirty, expectedSchemaVersion)
+	return nil
+}
+
+// CreateMigration создаёт пару пустых up/down SQL-файлов под новую миграцию
+// в db/migrations, с префиксом следующего порядкового номера.
+func CreateMigration(name string) error {
+	next := expectedSchemaVersion + 1
+	base := fmt.Sprintf("db/migrations/%04d_%s", next, name)
+	for _, suffix := range []string{".up.sql", ".down.sql"} {
+		path := base + suffix
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("-- %s\n", name)), 0o644); err != nil {
+			return fmt.Errorf("failed to create migration file %s: %w", path, err)
+		}
+		config.Log.Infof("Created migration file: %s", path)
+	}
+	return nil
+}
+
+// CheckSchemaVersion отказывает в старте HTTP-сервера, если версия схемы БД
+// не совпадает с версией, на которую рассчитан текущий бинарник - это не даёт
+// случайно запустить сервер со старой/рассинхронизированной базой.
+func CheckSchemaVersion() error {
+	m, err := newMigrate()
+	if err != nil {
+		return err
+	}
+	defer closeMigrate(m)
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read database schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d - run `migrate status` and fix it manually", version)
+	}
+	if int(version) != expectedSchemaVersion {
+		return fmt.Errorf("database schema version %d does not match expected version %d - run `go run . migrate up`", version, expectedSchemaVersion)
+	}
+	return nil
+}
+
+func closeMigrate(m *migrate.Migrate) {
+	sourceErr, dbErr := m.Close()
+	if sourceErr != nil {
+		config.Log.Warnf("Error closing migration source: %v", sourceErr)
+	}
+	if dbErr != nil {
+		config.Log.Warnf("Error closing migration database connection: %v", dbErr)
+	}
+}
@@ -0,0 +1,12 @@
+// graph/resolver.go
+package graph
+
+// This file will not be regenerated automatically.
+//
+// It serves as dependency injection for your app, add any dependencies you
+// require here.
+
+// Resolver - корневой резолвер GraphQL-схемы. Отдельных зависимостей не
+// требует: запросы идут напрямую через db.DB и services.EnrichPerson, как и
+// в REST-обработчиках (handlers/person.go).
+type Resolver struct{}
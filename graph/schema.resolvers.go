@@ -0,0 +1,225 @@
+// graph/schema.resolvers.go
+//
+// This file will be automatically regenerated based on the schema, any
+// resolver implementations will be copied through when generating and any
+// unknown code will be moved to the end.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"effective-mobile/auth"
+	"effective-mobile/config"
+	"effective-mobile/db"
+	"effective-mobile/graph/generated"
+	"effective-mobile/graph/model"
+	"effective-mobile/models"
+	"effective-mobile/services"
+)
+
+// errMissingWriteScope is returned by mutation resolvers (and the Enrichment
+// field resolver, which persists a write) when the caller's token lacks
+// people:write. /graphql itself only requires people:read, since it also
+// serves read-only queries - see main.go.
+var errMissingWriteScope = fmt.Errorf("missing required scope: people:write")
+
+// Enrichment is the resolver for the enrichment field on Person. It re-runs
+// services.EnrichPerson on demand and persists the refreshed fields, the
+// same way handlers.UpdatePerson does.
+func (r *personResolver) Enrichment(ctx context.Context, obj *models.Person) (*models.Person, error) {
+	if !auth.HasScope(ctx, "people:write") {
+		return nil, errMissingWriteScope
+	}
+	if err := services.EnrichPerson(obj); err != nil {
+		return nil, fmt.Errorf("failed to enrich person %d: %w", obj.ID, err)
+	}
+
+	subject := auth.Subject(ctx)
+	obj.UpdatedBy = subject
+	if err := db.DB.WithContext(models.WithActor(ctx, subject)).Save(obj).Error; err != nil {
+		return nil, fmt.Errorf("failed to persist re-enriched person %d: %w", obj.ID, err)
+	}
+	return obj, nil
+}
+
+// Person is the resolver for the person field.
+func (r *queryResolver) Person(ctx context.Context, id string) (*models.Person, error) {
+	var person models.Person
+	if err := db.DB.First(&person, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get person %s: %w", id, err)
+	}
+	return &person, nil
+}
+
+// People is the resolver for the people field.
+func (r *queryResolver) People(ctx context.Context, filter *model.PeopleFilter, sort *model.SortInput, page *int, limit *int) (*model.PeopleResult, error) {
+	query := applyGraphQLFilters(db.DB.Model(&models.Person{}), filter)
+	query = applyGraphQLSort(query, sort)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count people: %w", err)
+	}
+
+	pageNum, pageLimit := 1, 10
+	if page != nil {
+		pageNum = *page
+	}
+	if limit != nil {
+		pageLimit = *limit
+	}
+	offset := (pageNum - 1) * pageLimit
+
+	var people []*models.Person
+	if err := query.Limit(pageLimit).Offset(offset).Find(&people).Error; err != nil {
+		return nil, fmt.Errorf("failed to get people: %w", err)
+	}
+
+	return &model.PeopleResult{Items: people, Total: int(total)}, nil
+}
+
+// CreatePerson is the resolver for the createPerson field.
+func (r *mutationResolver) CreatePerson(ctx context.Context, input model.CreatePersonInput) (*models.Person, error) {
+	if !auth.HasScope(ctx, "people:write") {
+		return nil, errMissingWriteScope
+	}
+
+	subject := auth.Subject(ctx)
+	person := models.Person{
+		Name:       input.Name,
+		Surname:    input.Surname,
+		Patronymic: input.Patronymic,
+		CreatedBy:  subject,
+		UpdatedBy:  subject,
+	}
+
+	if err := services.EnrichPerson(&person); err != nil {
+		config.Log.Errorf("Error enriching person data: %v", err)
+	}
+
+	if err := db.DB.WithContext(models.WithActor(ctx, subject)).Create(&person).Error; err != nil {
+		return nil, fmt.Errorf("failed to create person: %w", err)
+	}
+	return &person, nil
+}
+
+// UpdatePerson is the resolver for the updatePerson field.
+func (r *mutationResolver) UpdatePerson(ctx context.Context, id string, input model.UpdatePersonInput) (*models.Person, error) {
+	if !auth.HasScope(ctx, "people:write") {
+		return nil, errMissingWriteScope
+	}
+
+	var person models.Person
+	if err := db.DB.First(&person, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to find person %s: %w", id, err)
+	}
+
+	if input.Name != nil {
+		person.Name = *input.Name
+	}
+	if input.Surname != nil {
+		person.Surname = *input.Surname
+	}
+	if input.Patronymic != nil {
+		person.Patronymic = input.Patronymic
+	}
+
+	subject := auth.Subject(ctx)
+	person.UpdatedBy = subject
+
+	if err := services.EnrichPerson(&person); err != nil {
+		config.Log.Errorf("Error re-enriching person data for ID %s: %v", id, err)
+	}
+
+	if err := db.DB.WithContext(models.WithActor(ctx, subject)).Save(&person).Error; err != nil {
+		return nil, fmt.Errorf("failed to update person %s: %w", id, err)
+	}
+	return &person, nil
+}
+
+// DeletePerson is the resolver for the deletePerson field.
+func (r *mutationResolver) DeletePerson(ctx context.Context, id string) (bool, error) {
+	if !auth.HasScope(ctx, "people:write") {
+		return false, errMissingWriteScope
+	}
+	var person models.Person
+	if err := db.DB.First(&person, id).Error; err != nil {
+		return false, fmt.Errorf("failed to find person %s: %w", id, err)
+	}
+	dbCtx := models.WithActor(ctx, auth.Subject(ctx))
+	if err := db.DB.WithContext(dbCtx).Delete(&person).Error; err != nil {
+		return false, fmt.Errorf("failed to delete person %s: %w", id, err)
+	}
+	return true, nil
+}
+
+// Person returns generated.PersonResolver implementation.
+func (r *Resolver) Person() generated.PersonResolver { return &personResolver{r} }
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type personResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+
+// applyGraphQLFilters mirrors handlers.applyPeopleFilters for the GraphQL
+// PeopleFilter input, so both APIs honor the same set of filters.
+func applyGraphQLFilters(query *gorm.DB, filter *model.PeopleFilter) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if filter.Name != nil && *filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+*filter.Name+"%")
+	}
+	if filter.Surname != nil && *filter.Surname != "" {
+		query = query.Where("surname ILIKE ?", "%"+*filter.Surname+"%")
+	}
+	if filter.Patronymic != nil && *filter.Patronymic != "" {
+		query = query.Where("patronymic ILIKE ?", "%"+*filter.Patronymic+"%")
+	}
+	if filter.Gender != nil && *filter.Gender != "" {
+		query = query.Where("gender ILIKE ?", "%"+*filter.Gender+"%")
+	}
+	if filter.Nationality != nil && *filter.Nationality != "" {
+		query = query.Where("nationality ILIKE ?", "%"+*filter.Nationality+"%")
+	}
+	if filter.AgeBetween != nil {
+		if filter.AgeBetween.Min != nil {
+			query = query.Where("age >= ?", *filter.AgeBetween.Min)
+		}
+		if filter.AgeBetween.Max != nil {
+			query = query.Where("age <= ?", *filter.AgeBetween.Max)
+		}
+	}
+	return query
+}
+
+func applyGraphQLSort(query *gorm.DB, sort *model.SortInput) *gorm.DB {
+	if sort == nil {
+		return query
+	}
+	column := map[model.SortField]string{
+		model.SortFieldName:    "name",
+		model.SortFieldSurname: "surname",
+		model.SortFieldAge:     "age",
+	}[sort.Field]
+	if column == "" {
+		return query
+	}
+	direction := "ASC"
+	if sort.Direction == model.SortDirectionDesc {
+		direction = "DESC"
+	}
+	return query.Order(column + " " + direction)
+}
@@ -0,0 +1,413 @@
+// handlers/bulk.go
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"effective-mobile/config"
+	"effective-mobile/db"
+	"effective-mobile/models"
+	"effective-mobile/services"
+)
+
+const (
+	csvColumns = "name,surname,patronymic"
+
+	defaultImportWorkers = 8
+	defaultImportBatch   = 100
+)
+
+// importRowResult - результат обработки одной строки импорта.
+// Отдаётся клиенту построчно в виде NDJSON, поэтому поля снабжены
+// тегами json и omitempty там, где значение не всегда имеет смысл.
+type importRowResult struct {
+	Line   int    `json:"line"`
+	ID     uint   `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// importJob - разобранная, но ещё не обогащённая строка импорта.
+type importJob struct {
+	line   int
+	person models.Person
+}
+
+// enrichedRow - строка, прошедшая обогащение (или упавшая с ошибкой на этом этапе).
+type enrichedRow struct {
+	line   int
+	person models.Person
+	err    error
+}
+
+// ImportPeople принимает поток строк в формате CSV (text/csv) или NDJSON
+// (application/x-ndjson) и обогащает+сохраняет их, отдавая клиенту
+// построчный NDJSON-поток результатов по мере обработки.
+//
+// @Summary Bulk import people
+// @Description Stream-import people from CSV or NDJSON, enriching and batch-inserting them. Emits one NDJSON result object per input row.
+// @Tags people
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce application/x-ndjson
+// @Param dry_run query bool false "Validate and enrich without writing to the database"
+// @Success 200 {string} string "NDJSON stream of {line, id, status, error}"
+// @Failure 400 {object} map[string]string
+// @Router /people/import [post]
+func ImportPeople(c *gin.Context) {
+	config.Log.Info("Handling ImportPeople request")
+
+	dryRun := c.Query("dry_run") == "true"
+	contentType := c.ContentType()
+	actor := actorSubject(c)
+	ctx := models.WithActor(c.Request.Context(), actor)
+
+	jobs := make(chan importJob)
+	enriched := make(chan enrichedRow)
+	results := make(chan importRowResult)
+
+	workers := envInt("IMPORT_WORKERS", defaultImportWorkers)
+	batchSize := envInt("IMPORT_BATCH_SIZE", defaultImportBatch)
+
+	var workerWG sync.WaitGroup
+	workerWG.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer workerWG.Done()
+			for job := range jobs {
+				person := job.person
+				err := services.EnrichPerson(&person)
+				enriched <- enrichedRow{line: job.line, person: person, err: err}
+			}
+		}()
+	}
+	go func() {
+		workerWG.Wait()
+		close(enriched)
+	}()
+
+	go batchInsertPeople(ctx, actor, enriched, results, batchSize, dryRun)
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "application/x-ndjson")
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	var writeWG sync.WaitGroup
+	writeWG.Add(1)
+	go func() {
+		defer writeWG.Done()
+		encoder := json.NewEncoder(c.Writer)
+		for res := range results {
+			if err := encoder.Encode(res); err != nil {
+				config.Log.Errorf("Failed to write import result for line %d: %v", res.Line, err)
+				continue
+			}
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+	}()
+
+	if err := parseImportBody(c, contentType, jobs); err != nil {
+		config.Log.Errorf("Failed to parse import body: %v", err)
+	}
+	close(jobs)
+
+	writeWG.Wait()
+	config.Log.Info("Finished ImportPeople request")
+}
+
+// parseImportBody стримово разбирает тело запроса построчно (без
+// полной буферизации) и отправляет разобранные строки в jobs.
+func parseImportBody(c *gin.Context, contentType string, jobs chan<- importJob) error {
+	body := c.Request.Body
+	defer body.Close()
+
+	if strings.Contains(contentType, "application/x-ndjson") {
+		return parseNDJSONBody(body, jobs)
+	}
+	return parseCSVBody(body, jobs)
+}
+
+func parseNDJSONBody(body io.Reader, jobs chan<- importJob) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var input models.PersonInput
+		if err := json.Unmarshal([]byte(raw), &input); err != nil {
+			jobs <- importJob{line: line, person: models.Person{}}
+			continue
+		}
+		jobs <- importJob{line: line, person: personFromInput(input)}
+	}
+	return scanner.Err()
+}
+
+func parseCSVBody(body io.Reader, jobs chan<- importJob) error {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columnIndex := make(map[string]int, len(header))
+	for i, col := range header {
+		columnIndex[strings.TrimSpace(strings.ToLower(col))] = i
+	}
+
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV record: %w", err)
+		}
+		line++
+		jobs <- importJob{line: line, person: personFromCSVRecord(record, columnIndex)}
+	}
+}
+
+func personFromInput(input models.PersonInput) models.Person {
+	return models.Person{
+		Name:       input.Name,
+		Surname:    input.Surname,
+		Patronymic: input.Patronymic,
+	}
+}
+
+func personFromCSVRecord(record []string, columnIndex map[string]int) models.Person {
+	get := func(col string) string {
+		idx, ok := columnIndex[col]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return record[idx]
+	}
+
+	person := models.Person{
+		Name:    get("name"),
+		Surname: get("surname"),
+	}
+	if patronymic := get("patronymic"); patronymic != "" {
+		person.Patronymic = &patronymic
+	}
+	return person
+}
+
+// batchInsertPeople собирает обогащённые строки в батчи по batchSize и
+// вставляет каждый батч одной транзакцией GORM, после чего публикует
+// результат по каждой строке в results. В режиме dry_run вставка в БД
+// не выполняется, но строки всё равно проходят валидацию/обогащение. ctx и
+// actor несут вызывающего (см. actorSubject/models.WithActor), чтобы
+// created_by/updated_by и person_audits.actor заполнялись так же, как при
+// создании через CreatePerson.
+func batchInsertPeople(ctx context.Context, actor *string, enriched <-chan enrichedRow, results chan<- importRowResult, batchSize int, dryRun bool) {
+	defer close(results)
+
+	batch := make([]enrichedRow, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		insertBatch(ctx, actor, batch, results, dryRun)
+		batch = batch[:0]
+	}
+
+	for row := range enriched {
+		if row.err != nil || row.person.Name == "" {
+			status := "invalid"
+			errMsg := "name is required"
+			if row.err != nil {
+				status = "enrichment_failed"
+				errMsg = row.err.Error()
+			}
+			results <- importRowResult{Line: row.line, Status: status, Error: errMsg}
+			continue
+		}
+		batch = append(batch, row)
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+	flush()
+}
+
+func insertBatch(ctx context.Context, actor *string, batch []enrichedRow, results chan<- importRowResult, dryRun bool) {
+	if dryRun {
+		for _, row := range batch {
+			results <- importRowResult{Line: row.line, Status: "validated"}
+		}
+		return
+	}
+
+	people := make([]models.Person, len(batch))
+	for i, row := range batch {
+		people[i] = row.person
+		people[i].CreatedBy = actor
+		people[i].UpdatedBy = actor
+	}
+
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&people).Error
+	})
+	if err != nil {
+		config.Log.Errorf("Failed to insert import batch: %v", err)
+		for _, row := range batch {
+			results <- importRowResult{Line: row.line, Status: "error", Error: err.Error()}
+		}
+		return
+	}
+
+	for i, row := range batch {
+		results <- importRowResult{Line: row.line, ID: people[i].ID, Status: "created"}
+	}
+}
+
+// ExportPeople стримит людей, удовлетворяющих тем же фильтрам, что и
+// GetPeople, в формате CSV или NDJSON (по заголовку Accept), используя
+// rows.Next()+db.ScanRows вместо загрузки всего результата в память.
+//
+// @Summary Export people
+// @Description Stream-export people matching the same filters as GET /people, as CSV or NDJSON depending on the Accept header
+// @Tags people
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param name query string false "Filter by name"
+// @Param surname query string false "Filter by surname"
+// @Param patronymic query string false "Filter by patronymic"
+// @Param age_min query int false "Filter by minimum age"
+// @Param age_max query int false "Filter by maximum age"
+// @Param gender query string false "Filter by gender"
+// @Param nationality query string false "Filter by nationality"
+// @Param include_deleted query bool false "Include soft-deleted people"
+// @Success 200 {string} string "CSV or NDJSON stream of people"
+// @Failure 500 {object} map[string]string
+// @Router /people/export [get]
+func ExportPeople(c *gin.Context) {
+	config.Log.Info("Handling ExportPeople request")
+
+	base := db.DB.Model(&models.Person{})
+	if c.Query("include_deleted") == "true" {
+		base = base.Unscoped()
+	}
+	query := applyPeopleFilters(c, base)
+	rows, err := query.Rows()
+	if err != nil {
+		config.Log.Errorf("Error opening export cursor: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export people"})
+		return
+	}
+	defer rows.Close()
+
+	if strings.Contains(c.GetHeader("Accept"), "application/x-ndjson") {
+		exportNDJSON(c, rows)
+		return
+	}
+	exportCSV(c, rows)
+}
+
+func exportNDJSON(c *gin.Context, rows *sql.Rows) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "application/x-ndjson")
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	encoder := json.NewEncoder(c.Writer)
+	for rows.Next() {
+		var person models.Person
+		if err := db.DB.ScanRows(rows, &person); err != nil {
+			config.Log.Errorf("Error scanning person row during export: %v", err)
+			continue
+		}
+		if err := encoder.Encode(person); err != nil {
+			config.Log.Errorf("Failed to write export row: %v", err)
+			continue
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+func exportCSV(c *gin.Context, rows *sql.Rows) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/csv")
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	writer := csv.NewWriter(c.Writer)
+	header := strings.Split(csvColumns, ",")
+	header = append(header, "age", "gender", "nationality")
+	_ = writer.Write(header)
+
+	for rows.Next() {
+		var person models.Person
+		if err := db.DB.ScanRows(rows, &person); err != nil {
+			config.Log.Errorf("Error scanning person row during export: %v", err)
+			continue
+		}
+		_ = writer.Write([]string{
+			person.Name,
+			person.Surname,
+			stringOrEmpty(person.Patronymic),
+			intOrEmpty(person.Age),
+			stringOrEmpty(person.Gender),
+			stringOrEmpty(person.Nationality),
+		})
+		writer.Flush()
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intOrEmpty(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func envInt(name string, fallback int) int {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil || parsed <= 0 {
+		return fallback
+	}
+	return parsed
+}
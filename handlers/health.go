@@ -0,0 +1,27 @@
+// handlers/health.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"effective-mobile/services"
+)
+
+// Healthz сообщает состояние circuit breaker каждого провайдера обогащения
+// (agify/genderize/nationalize), чтобы можно было быстро увидеть, какие из
+// них сейчас недоступны, не заглядывая в логи.
+//
+// @Summary Health check
+// @Description Reports the circuit breaker state of each enrichment provider
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /healthz [get]
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "ok",
+		"breakers": services.BreakerStates(),
+	})
+}
@@ -28,6 +28,7 @@ import (
 // @Param nationality query string false "Filter by nationality"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Number of items per page" default(10)
+// @Param include_deleted query bool false "Include soft-deleted people"
 // @Success 200 {array} models.Person
 // @Failure 500 {object} map[string]string
 // @Router /people [get]
@@ -35,41 +36,11 @@ func GetPeople(c *gin.Context) {
 	config.Log.Info("Handling GetPeople request")
 
 	var people []models.Person
-	query := db.DB
-
-	// Фильтры
-	if name := c.Query("name"); name != "" {
-		query = query.Where("name ILIKE ?", "%"+name+"%")
-		config.Log.Debugf("Filtering by name: %s", name)
-	}
-	if surname := c.Query("surname"); surname != "" {
-		query = query.Where("surname ILIKE ?", "%"+surname+"%")
-		config.Log.Debugf("Filtering by surname: %s", surname)
-	}
-	if patronymic := c.Query("patronymic"); patronymic != "" {
-		query = query.Where("patronymic ILIKE ?", "%"+patronymic+"%")
-		config.Log.Debugf("Filtering by patronymic: %s", patronymic)
-	}
-	if ageMinStr := c.Query("age_min"); ageMinStr != "" {
-		if ageMin, err := strconv.Atoi(ageMinStr); err == nil {
-			query = query.Where("age >= ?", ageMin)
-			config.Log.Debugf("Filtering by min age: %d", ageMin)
-		}
-	}
-	if ageMaxStr := c.Query("age_max"); ageMaxStr != "" {
-		if ageMax, err := strconv.Atoi(ageMaxStr); err == nil {
-			query = query.Where("age <= ?", ageMax)
-			config.Log.Debugf("Filtering by max age: %d", ageMax)
-		}
-	}
-	if gender := c.Query("gender"); gender != "" {
-		query = query.Where("gender ILIKE ?", "%"+gender+"%")
-		config.Log.Debugf("Filtering by gender: %s", gender)
-	}
-	if nationality := c.Query("nationality"); nationality != "" {
-		query = query.Where("nationality ILIKE ?", "%"+nationality+"%")
-		config.Log.Debugf("Filtering by nationality: %s", nationality)
+	base := db.DB
+	if c.Query("include_deleted") == "true" {
+		base = base.Unscoped()
 	}
+	query := applyPeopleFilters(c, base)
 
 	// Пагинация
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -112,6 +83,10 @@ func CreatePerson(c *gin.Context) {
 		Surname:    inputPerson.Surname,
 		Patronymic: inputPerson.Patronymic,
 	}
+	if subject := actorSubject(c); subject != nil {
+		person.CreatedBy = subject
+		person.UpdatedBy = subject
+	}
 
 	// Обогащаем данные
 	if err := services.EnrichPerson(&person); err != nil {
@@ -120,7 +95,8 @@ func CreatePerson(c *gin.Context) {
 		// Для данного ТЗ, лучше продолжить и сохранить то, что есть, логгируя ошибку обогащения.
 	}
 
-	if err := db.DB.Create(&person).Error; err != nil {
+	ctx := models.WithActor(c.Request.Context(), actorSubject(c))
+	if err := db.DB.WithContext(ctx).Create(&person).Error; err != nil {
 		config.Log.Errorf("Error creating person in DB: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create person"})
 		return
@@ -202,6 +178,9 @@ func UpdatePerson(c *gin.Context) {
 	if updateInput.Patronymic != nil {
 		existingPerson.Patronymic = updateInput.Patronymic
 	}
+	if subject := actorSubject(c); subject != nil {
+		existingPerson.UpdatedBy = subject
+	}
 
 	// Если имя изменилось, то нужно переобогатить данные
 	// Для простоты, переобогащаем всегда при обновлении
@@ -210,7 +189,8 @@ func UpdatePerson(c *gin.Context) {
 		config.Log.Errorf("Error re-enriching person data for ID %s: %v", id, err)
 	}
 
-	if err := db.DB.Save(&existingPerson).Error; err != nil {
+	ctx := models.WithActor(c.Request.Context(), actorSubject(c))
+	if err := db.DB.WithContext(ctx).Save(&existingPerson).Error; err != nil {
 		config.Log.Errorf("Error updating person with ID %s in DB: %v", id, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update person"})
 		return
@@ -245,7 +225,8 @@ func DeletePerson(c *gin.Context) {
 		return
 	}
 
-	if err := db.DB.Delete(&person).Error; err != nil {
+	ctx := models.WithActor(c.Request.Context(), actorSubject(c))
+	if err := db.DB.WithContext(ctx).Delete(&person).Error; err != nil {
 		config.Log.Errorf("Error deleting person with ID %s from DB: %v", id, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete person"})
 		return
@@ -255,9 +236,77 @@ func DeletePerson(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// Добавим структуру для входных данных, как в ТЗ
-type PersonInput struct {
-	Name       string  `json:"name" binding:"required"`
-	Surname    string  `json:"surname" binding:"required"`
-	Patronymic *string `json:"patronymic,omitempty"`
+// @Summary Get a person's audit history
+// @Description Get the full create/update/delete audit trail for a person, including before/after JSON snapshots and the actor who made each change
+// @Tags people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {array} models.PersonAudit
+// @Failure 500 {object} map[string]string
+// @Router /people/{id}/history [get]
+func GetPersonHistory(c *gin.Context) {
+	config.Log.Info("Handling GetPersonHistory request")
+	id := c.Param("id")
+
+	var audits []models.PersonAudit
+	if err := db.DB.Where("person_id = ?", id).Order("at ASC").Find(&audits).Error; err != nil {
+		config.Log.Errorf("Error getting history for person %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve person history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, audits)
+}
+
+// applyPeopleFilters применяет к запросу фильтры из query-параметров запроса.
+// Вынесено из GetPeople, чтобы те же фильтры можно было переиспользовать
+// в обработчике экспорта (handlers/bulk.go).
+func applyPeopleFilters(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if name := c.Query("name"); name != "" {
+		query = query.Where("name ILIKE ?", "%"+name+"%")
+		config.Log.Debugf("Filtering by name: %s", name)
+	}
+	if surname := c.Query("surname"); surname != "" {
+		query = query.Where("surname ILIKE ?", "%"+surname+"%")
+		config.Log.Debugf("Filtering by surname: %s", surname)
+	}
+	if patronymic := c.Query("patronymic"); patronymic != "" {
+		query = query.Where("patronymic ILIKE ?", "%"+patronymic+"%")
+		config.Log.Debugf("Filtering by patronymic: %s", patronymic)
+	}
+	if ageMinStr := c.Query("age_min"); ageMinStr != "" {
+		if ageMin, err := strconv.Atoi(ageMinStr); err == nil {
+			query = query.Where("age >= ?", ageMin)
+			config.Log.Debugf("Filtering by min age: %d", ageMin)
+		}
+	}
+	if ageMaxStr := c.Query("age_max"); ageMaxStr != "" {
+		if ageMax, err := strconv.Atoi(ageMaxStr); err == nil {
+			query = query.Where("age <= ?", ageMax)
+			config.Log.Debugf("Filtering by max age: %d", ageMax)
+		}
+	}
+	if gender := c.Query("gender"); gender != "" {
+		query = query.Where("gender ILIKE ?", "%"+gender+"%")
+		config.Log.Debugf("Filtering by gender: %s", gender)
+	}
+	if nationality := c.Query("nationality"); nationality != "" {
+		query = query.Where("nationality ILIKE ?", "%"+nationality+"%")
+		config.Log.Debugf("Filtering by nationality: %s", nationality)
+	}
+	return query
+}
+
+// actorSubject возвращает subject OIDC-токена текущего запроса (см.
+// auth.RequireScope), если аутентификация включена, иначе nil.
+func actorSubject(c *gin.Context) *string {
+	subject, ok := c.Get("subject")
+	if !ok {
+		return nil
+	}
+	str, ok := subject.(string)
+	if !ok || str == "" {
+		return nil
+	}
+	return &str
 }
\ No newline at end of file
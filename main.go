@@ -5,14 +5,20 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"effective-mobile/auth"
+	"effective-mobile/cache"
 	"effective-mobile/config"
 	"effective-mobile/db"
 	"effective-mobile/docs" // Сгенерированный Swagger
+	"effective-mobile/graph"
+	"effective-mobile/graph/generated"
 	"effective-mobile/handlers"
 )
 
@@ -33,17 +39,32 @@ import (
 func main() {
 	// Инициализация логгера
 	config.InitLogger()
-	config.Log.Info("Starting Human Enrichment Service")
 
 	// Загрузка переменных окружения
-	err := godotenv.Load()
-	if err != nil {
+	if err := godotenv.Load(); err != nil {
 		config.Log.Fatalf("Error loading .env file: %v", err)
 	}
 
+	// `go run . migrate up|down|status|create <name>` управляет схемой БД
+	// независимо от HTTP-сервера и не должен его поднимать.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
+	config.Log.Info("Starting Human Enrichment Service")
+
 	// Инициализация базы данных
 	db.InitDB()
 
+	// Инициализация кэша обогащения (in-memory LRU или Redis, см. CACHE_BACKEND)
+	cache.InitCache()
+
+	// Инициализация OIDC-аутентификации (AUTH_MODE=disabled для локальной разработки)
+	if err := auth.Init(); err != nil {
+		config.Log.Fatalf("Failed to initialize auth: %v", err)
+	}
+
 	router := gin.Default()
 
 	// Настройка Swagger
@@ -51,16 +72,37 @@ func main() {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	config.Log.Info("Swagger UI available at /swagger/index.html")
 
+	// Метрики кэша обогащения в формате Prometheus
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Состояние circuit breaker'ов провайдеров обогащения
+	router.GET("/healthz", handlers.Healthz)
+
 	// Группировка роутов
 	v1 := router.Group("/api/v1")
 	{
 		people := v1.Group("/people")
 		{
-			people.GET("", handlers.GetPeople)
-			people.GET("/:id", handlers.GetPersonByID)
-			people.POST("", handlers.CreatePerson)
-			people.PUT("/:id", handlers.UpdatePerson)
-			people.DELETE("/:id", handlers.DeletePerson)
+			people.GET("", auth.RequireScope("people:read"), handlers.GetPeople)
+			people.GET("/:id", auth.RequireScope("people:read"), handlers.GetPersonByID)
+			people.GET("/:id/history", auth.RequireScope("people:read"), handlers.GetPersonHistory)
+			people.POST("", auth.RequireScope("people:write"), handlers.CreatePerson)
+			people.PUT("/:id", auth.RequireScope("people:write"), handlers.UpdatePerson)
+			people.DELETE("/:id", auth.RequireScope("people:write"), handlers.DeletePerson)
+			people.POST("/import", auth.RequireScope("people:write"), handlers.ImportPeople)
+			people.GET("/export", auth.RequireScope("people:read"), handlers.ExportPeople)
+		}
+
+		// GraphQL-поверхность поверх тех же моделей и services.EnrichPerson.
+		// /graphql обслуживает и запросы, и мутации одним POST-роутом, поэтому
+		// здесь проверяется только минимальный people:read scope; мутации
+		// дополнительно требуют people:write через auth.HasScope в резолверах
+		// (graph/schema.resolvers.go).
+		gqlServer := generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}})
+		v1.POST("/graphql", auth.RequireScope("people:read"), gin.WrapH(gqlServer))
+		if gin.Mode() == gin.DebugMode {
+			v1.GET("/playground", gin.WrapH(playground.Handler("GraphQL Playground", "/api/v1/graphql")))
+			config.Log.Info("GraphQL playground available at /api/v1/playground")
 		}
 	}
 
@@ -73,4 +115,32 @@ func main() {
 	if err := router.Run(fmt.Sprintf(":%s", port)); err != nil {
 		config.Log.Fatalf("Server failed to start: %v", err)
 	}
+}
+
+// runMigrateCommand обрабатывает `go run . migrate <up|down|status|create>`.
+func runMigrateCommand(args []string) {
+	if len(args) == 0 {
+		config.Log.Fatal("Usage: migrate <up|down|status|create> [name]")
+	}
+
+	var err error
+	switch args[0] {
+	case "up":
+		err = db.MigrateUp()
+	case "down":
+		err = db.MigrateDown()
+	case "status":
+		err = db.MigrationStatus()
+	case "create":
+		if len(args) < 2 {
+			config.Log.Fatal("Usage: migrate create <name>")
+		}
+		err = db.CreateMigration(args[1])
+	default:
+		config.Log.Fatalf("Unknown migrate subcommand: %s", args[0])
+	}
+
+	if err != nil {
+		config.Log.Fatalf("migrate %s failed: %v", args[0], err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+// models/context.go
+package models
+
+import "context"
+
+type actorContextKey struct{}
+
+// WithActor attaches the acting OIDC subject (see auth.RequireScope) to ctx,
+// so the GORM hooks on Person can stamp it onto person_audits rows. Pass the
+// resulting context to db.DB.WithContext(...) before Create/Save/Delete.
+func WithActor(ctx context.Context, actor *string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func actorFromContext(ctx context.Context) *string {
+	actor, _ := ctx.Value(actorContextKey{}).(*string)
+	return actor
+}
@@ -0,0 +1,115 @@
+// models/person.go
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Person - основная модель сервиса. Поля Age/Gender/Nationality - указатели,
+// поскольку обогащение (services.EnrichPerson) не гарантирует, что внешние
+// провайдеры вернут значение для каждого имени.
+type Person struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	Name        string  `gorm:"not null" json:"name"`
+	Surname     string  `gorm:"not null" json:"surname"`
+	Patronymic  *string `json:"patronymic,omitempty"`
+	Age         *int    `json:"age,omitempty"`
+	Gender      *string `json:"gender,omitempty"`
+	Nationality *string `json:"nationality,omitempty"`
+
+	// CreatedAt/UpdatedAt заполняются GORM автоматически (см. 0003_people_timestamps).
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// CreatedBy/UpdatedBy - subject обладателя OIDC-токена, создавшего запись
+	// и сделавшего последнее изменение записи соответственно (см.
+	// auth.RequireScope). Пусты, пока AUTH_MODE=disabled.
+	CreatedBy *string `json:"created_by,omitempty"`
+	UpdatedBy *string `json:"updated_by,omitempty"`
+
+	// DeletedAt делает DeletePerson мягким удалением: GORM сам подставляет
+	// "deleted_at IS NULL" во все запросы, пока не вызван Unscoped().
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// auditBefore хранит состояние записи до изменения, снятое в
+	// BeforeUpdate/BeforeDelete, чтобы After-хук мог записать полную
+	// before/after пару в person_audits. Не является колонкой БД.
+	auditBefore *Person `gorm:"-" json:"-"`
+}
+
+// PersonInput - тело запроса на создание/обновление Person.
+type PersonInput struct {
+	Name       string  `json:"name" binding:"required"`
+	Surname    string  `json:"surname" binding:"required"`
+	Patronymic *string `json:"patronymic,omitempty"`
+}
+
+// PersonAudit - запись аудита одного изменения Person (see GetPersonHistory).
+type PersonAudit struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PersonID   uint      `gorm:"index;not null" json:"person_id"`
+	Op         string    `gorm:"not null" json:"op"`
+	BeforeJSON string    `json:"before_json,omitempty"`
+	AfterJSON  string    `json:"after_json,omitempty"`
+	Actor      *string   `json:"actor,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// AfterCreate пишет в person_audits запись о создании Person.
+func (p *Person) AfterCreate(tx *gorm.DB) error {
+	return writePersonAudit(tx, p.ID, "create", nil, p)
+}
+
+// BeforeUpdate снимает состояние записи до изменения, чтобы AfterUpdate мог
+// сохранить полную before/after пару.
+func (p *Person) BeforeUpdate(tx *gorm.DB) error {
+	p.auditBefore = loadAuditSnapshot(tx, p.ID)
+	return nil
+}
+
+// AfterUpdate пишет в person_audits запись об изменении Person.
+func (p *Person) AfterUpdate(tx *gorm.DB) error {
+	return writePersonAudit(tx, p.ID, "update", p.auditBefore, p)
+}
+
+// BeforeDelete снимает состояние записи перед (мягким) удалением.
+func (p *Person) BeforeDelete(tx *gorm.DB) error {
+	p.auditBefore = loadAuditSnapshot(tx, p.ID)
+	return nil
+}
+
+// AfterDelete пишет в person_audits запись об удалении Person.
+func (p *Person) AfterDelete(tx *gorm.DB) error {
+	return writePersonAudit(tx, p.ID, "delete", p.auditBefore, nil)
+}
+
+func loadAuditSnapshot(tx *gorm.DB, id uint) *Person {
+	var existing Person
+	if err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().First(&existing, id).Error; err != nil {
+		return nil
+	}
+	return &existing
+}
+
+func writePersonAudit(tx *gorm.DB, personID uint, op string, before, after *Person) error {
+	audit := PersonAudit{
+		PersonID: personID,
+		Op:       op,
+		Actor:    actorFromContext(tx.Statement.Context),
+		At:       time.Now(),
+	}
+	if before != nil {
+		if data, err := json.Marshal(before); err == nil {
+			audit.BeforeJSON = string(data)
+		}
+	}
+	if after != nil {
+		if data, err := json.Marshal(after); err == nil {
+			audit.AfterJSON = string(data)
+		}
+	}
+	return tx.Session(&gorm.Session{NewDB: true}).Create(&audit).Error
+}
@@ -0,0 +1,111 @@
+// services/breaker.go
+package services
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	"effective-mobile/config"
+)
+
+const (
+	defaultEnrichTimeout   = 5 * time.Second
+	defaultEnrichRetries   = 2
+	defaultBreakerFailures = 5
+)
+
+// breakersMu guards breakers: EnrichPerson fans out to 3 providers per call via
+// errgroup, and bulk import (handlers.ImportPeople) runs up to IMPORT_WORKERS of
+// those concurrently, so breakerFor and BreakerStates race on the map without it.
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*gobreaker.CircuitBreaker{}
+)
+
+// breakerFor возвращает (создавая при необходимости) circuit breaker для
+// provider. Breaker открывается после ENRICH_BREAKER_THRESHOLD подряд неудач
+// и переходит в half-open через Timeout, как и принято в gobreaker.
+func breakerFor(provider string) *gobreaker.CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	if cb, ok := breakers[provider]; ok {
+		return cb
+	}
+
+	threshold := uint32(envInt("ENRICH_BREAKER_THRESHOLD", defaultBreakerFailures))
+	cb := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name: provider,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= threshold
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			config.Log.Warnf("Circuit breaker for %s changed state: %s -> %s", name, from, to)
+		},
+	})
+	breakers[provider] = cb
+	return cb
+}
+
+// BreakerStates возвращает текущее состояние circuit breaker каждого провайдера
+// обогащения (agify/genderize/nationalize - в том числе ещё не вызывавшихся) и
+// любого другого известного провайдера, используется обработчиком GET /healthz.
+func BreakerStates() map[string]string {
+	for _, provider := range []string{"agify", "genderize", "nationalize"} {
+		breakerFor(provider)
+	}
+
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	states := make(map[string]string, len(breakers))
+	for provider, cb := range breakers {
+		states[provider] = cb.State().String()
+	}
+	return states
+}
+
+// EnrichTimeout - таймаут одного обращения к провайдеру, включая ретраи.
+func EnrichTimeout() time.Duration { return envDuration("ENRICH_TIMEOUT", defaultEnrichTimeout) }
+
+// enrichRetries - число повторов для retryable-ответов (429/5xx).
+func enrichRetries() int { return envInt("ENRICH_RETRIES", defaultEnrichRetries) }
+
+// backoffWithJitter возвращает задержку перед попыткой attempt (с отсчётом от 0)
+// по схеме экспоненциального backoff с джиттером, чтобы параллельные ретраи
+// не били по провайдеру одновременно.
+func backoffWithJitter(attempt int) time.Duration {
+	base := 50 * time.Millisecond
+	backoff := base * time.Duration(1<<attempt)
+	jitter := time.Duration(rand.Int63n(int64(backoff) + 1))
+	return backoff/2 + jitter/2
+}
+
+func envInt(name string, fallback int) int {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(val)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
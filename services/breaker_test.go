@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBreakerForReturnsSameInstance(t *testing.T) {
+	a := breakerFor("test-provider-same")
+	b := breakerFor("test-provider-same")
+	if a != b {
+		t.Fatalf("expected breakerFor to return the same breaker for repeated calls with the same provider")
+	}
+}
+
+func TestBreakerForDifferentProviders(t *testing.T) {
+	a := breakerFor("test-provider-a")
+	b := breakerFor("test-provider-b")
+	if a == b {
+		t.Fatalf("expected breakerFor to return distinct breakers for distinct providers")
+	}
+}
+
+// TestBreakerForConcurrent exercises the map access that used to race under
+// concurrent EnrichPerson calls (see breakersMu) - run with -race to confirm.
+func TestBreakerForConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			breakerFor("test-provider-concurrent")
+			BreakerStates()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBackoffWithJitterNonNegative(t *testing.T) {
+	for attempt := 0; attempt < 5; attempt++ {
+		if d := backoffWithJitter(attempt); d < 0 {
+			t.Fatalf("backoffWithJitter(%d) returned negative duration: %v", attempt, d)
+		}
+	}
+}
+
+func TestEnvIntFallback(t *testing.T) {
+	if got := envInt("ENRICH_TEST_UNSET_INT", 7); got != 7 {
+		t.Fatalf("expected fallback 7, got %d", got)
+	}
+}
+
+func TestEnvDurationFallback(t *testing.T) {
+	if got := envDuration("ENRICH_TEST_UNSET_DURATION", defaultEnrichTimeout); got != defaultEnrichTimeout {
+		t.Fatalf("expected fallback %v, got %v", defaultEnrichTimeout, got)
+	}
+}
@@ -1,11 +1,20 @@
 package services
 
 import (
+	"context"       // Для таймаута и отмены параллельных обращений к провайдерам
 	"encoding/json" // Для работы с JSON (парсинг ответов от API)
-	"fmt"           // Для форматирования строк (например, URL-адресов)
-	"net/http"      // Для выполнения HTTP-запросов
-	"time"          // Для установки таймаута HTTP-клиента
+	"errors"
+	"fmt"      // Для форматирования строк (например, URL-адресов)
+	"net/http" // Для выполнения HTTP-запросов
+	"strconv"  // Для преобразования возраста в строку для кэша
+	"strings"  // Для нормализации имени перед использованием как ключа кэша
+	"time"     // Для установки таймаута HTTP-клиента
 
+	"github.com/sony/gobreaker"
+	"golang.org/x/sync/errgroup"     // Для параллельного вызова трёх провайдеров
+	"golang.org/x/sync/singleflight" // Для схлопывания одновременных запросов по одному имени
+
+	"effective-mobile/cache"  // Кэш результатов обогащения (LRU или Redis)
 	"effective-mobile/config" // Импортируем наш логгер
 	"effective-mobile/models" // Импортируем нашу модель Person
 )
@@ -27,68 +36,225 @@ type GenderizeResponse struct {
 // NationalizeResponse представляет структуру ответа от api.nationalize.io
 type NationalizeResponse struct {
 	Country []struct { // nationalize.io возвращает массив стран
-		CountryID   string  `json:"country_id"`   // Код страны (например, "US", "RU")
+		CountryID   string  `json:"country_id"`  // Код страны (например, "US", "RU")
 		Probability float64 `json:"probability"` // Вероятность
 	} `json:"country"`
 	// Другие поля в ответе (count, name) нам не нужны.
 }
 
+// sfGroup схлопывает одновременные запросы разных горутин за одним и тем же
+// провайдером/именем в один фактический HTTP-вызов - это особенно важно во
+// время массового импорта, где одно и то же имя может встретиться много раз
+// в параллельно обрабатываемых строках.
+var sfGroup singleflight.Group
+
 // --- Основная функция обогащения данных ---
 
-// EnrichPerson принимает указатель на структуру models.Person
-// и пытается обогатить её полями Age, Gender и Nationality,
-// обращаясь к внешним API.
+// EnrichPerson принимает указатель на структуру models.Person и пытается
+// обогатить её полями Age, Gender и Nationality, обращаясь к внешним API
+// параллельно (через errgroup), с общим таймаутом ENRICH_TIMEOUT. Каждый
+// провайдер защищён отдельным circuit breaker: если он открыт, провайдер
+// пропускается немедленно, а запрос CreatePerson/UpdatePerson не блокируется.
 func EnrichPerson(person *models.Person) error {
 	config.Log.Debugf("Начинаем обогащение данных для человека: %s", person.Name)
 
-	// Создаем HTTP-клиент с таймаутом, чтобы запросы не висли бесконечно.
-	client := http.Client{Timeout: 5 * time.Second}
-
-	// --- Обогащение возрастом (agify.io) ---
-	ageURL := fmt.Sprintf("https://api.agify.io/?name=%s", person.Name)
-	var agifyRes AgifyResponse
-	// Вызываем вспомогательную функцию fetchData для выполнения запроса и парсинга ответа.
-	if err := fetchData(client, ageURL, &agifyRes); err != nil {
-		// Логируем ошибку, но не возвращаем её, так как это не критично.
-		config.Log.Warnf("Не удалось обогатить возраст для %s: %v", person.Name, err)
-	} else if agifyRes.Age != 0 { // Проверяем, что возраст получен
-		person.Age = &agifyRes.Age // Присваиваем указателю на int
-		config.Log.Debugf("Обогащен возраст для %s: %d", person.Name, *person.Age)
+	ctx, cancel := context.WithTimeout(context.Background(), EnrichTimeout())
+	defer cancel()
+
+	client := http.Client{Timeout: EnrichTimeout()}
+
+	var group errgroup.Group
+
+	group.Go(func() error {
+		age, ok := lookupAge(ctx, client, person.Name)
+		if ok {
+			person.Age = &age
+			config.Log.Debugf("Обогащен возраст для %s: %d", person.Name, age)
+		}
+		return nil
+	})
+	group.Go(func() error {
+		gender, ok := lookupGender(ctx, client, person.Name)
+		if ok {
+			person.Gender = &gender
+			config.Log.Debugf("Обогащен пол для %s: %s", person.Name, gender)
+		}
+		return nil
+	})
+	group.Go(func() error {
+		nationality, ok := lookupNationality(ctx, client, person.Name)
+		if ok {
+			person.Nationality = &nationality
+			config.Log.Debugf("Обогащена национальность для %s: %s", person.Name, nationality)
+		}
+		return nil
+	})
+
+	_ = group.Wait() // Каждая горутина сама логирует свои ошибки и не возвращает их наверх.
+
+	config.Log.Debugf("Завершено обогащение данных для человека: %s", person.Name)
+	return nil // Возвращаем nil, если обогащение прошло без критических ошибок.
+}
+
+// lookupAge обращается к agify.io через кэш, singleflight и circuit breaker,
+// и возвращает возраст и признак того, что он был получен.
+func lookupAge(ctx context.Context, client http.Client, name string) (int, bool) {
+	value, ok := cachedLookup(ctx, "agify", name, func() (string, error) {
+		ageURL := fmt.Sprintf("https://api.agify.io/?name=%s", name)
+		var res AgifyResponse
+		if err := fetchWithRetry(ctx, client, ageURL, &res); err != nil {
+			return "", err
+		}
+		if res.Age == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(res.Age), nil
+	})
+	if !ok {
+		return 0, false
+	}
+	age, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return age, true
+}
+
+// lookupGender обращается к genderize.io через кэш, singleflight и circuit breaker.
+func lookupGender(ctx context.Context, client http.Client, name string) (string, bool) {
+	return cachedLookup(ctx, "genderize", name, func() (string, error) {
+		genderURL := fmt.Sprintf("https://api.genderize.io/?name=%s", name)
+		var res GenderizeResponse
+		if err := fetchWithRetry(ctx, client, genderURL, &res); err != nil {
+			return "", err
+		}
+		return res.Gender, nil
+	})
+}
+
+// lookupNationality обращается к nationalize.io через кэш, singleflight и circuit
+// breaker. Кэшируется только самая вероятная страна (первая в ответе), как и раньше.
+func lookupNationality(ctx context.Context, client http.Client, name string) (string, bool) {
+	return cachedLookup(ctx, "nationalize", name, func() (string, error) {
+		nationalityURL := fmt.Sprintf("https://api.nationalize.io/?name=%s", name)
+		var res NationalizeResponse
+		if err := fetchWithRetry(ctx, client, nationalityURL, &res); err != nil {
+			return "", err
+		}
+		if len(res.Country) == 0 {
+			return "", nil
+		}
+		return res.Country[0].CountryID, nil
+	})
+}
+
+// cachedLookup проверяет кэш обогащения для provider/name. При промахе
+// вызывает fetch ровно один раз на конкурирующие вызовы (singleflight) через
+// circuit breaker провайдера: если breaker открыт, поле помечается
+// недоступным в структурированных логах и запрос не блокируется. Успешный
+// или отрицательный результат кэшируется на соответствующий TTL.
+func cachedLookup(ctx context.Context, provider, name string, fetch func() (string, error)) (string, bool) {
+	key := provider + ":" + strings.ToLower(name)
+
+	if value, negative, found := cache.Cache.Get(key); found {
+		cache.Observe(provider, negative, found)
+		if negative {
+			return "", false
+		}
+		return value, true
 	}
+	cache.Observe(provider, false, false)
+
+	result, err, _ := sfGroup.Do(key, func() (interface{}, error) {
+		value, err := breakerFor(provider).Execute(func() (interface{}, error) {
+			return fetch()
+		})
+		if err != nil {
+			return "", err
+		}
+		str, _ := value.(string)
+		if str == "" {
+			cache.Cache.Set(key, "", true, cache.NegativeTTL())
+		} else {
+			cache.Cache.Set(key, str, false, cache.PositiveTTL())
+		}
+		return str, nil
+	})
 
-	// --- Обогащение полом (genderize.io) ---
-	genderURL := fmt.Sprintf("https://api.genderize.io/?name=%s", person.Name)
-	var genderizeRes GenderizeResponse
-	if err := fetchData(client, genderURL, &genderizeRes); err != nil {
-		config.Log.Warnf("Не удалось обогатить пол для %s: %v", person.Name, err)
-	} else if genderizeRes.Gender != "" { // Проверяем, что пол получен
-		person.Gender = &genderizeRes.Gender // Присваиваем указателю на string
-		config.Log.Debugf("Обогащен пол для %s: %s", person.Name, *person.Gender)
+	if err != nil {
+		if errors.Is(err, gobreaker.ErrOpenState) {
+			config.Log.WithField("provider", provider).WithField("name", name).
+				Warn("Provider circuit breaker open, field marked unavailable")
+		} else {
+			config.Log.WithField("provider", provider).WithField("name", name).
+				Warnf("Provider lookup failed: %v", err)
+		}
+		return "", false
 	}
 
-	// --- Обогащение национальностью (nationalize.io) ---
-	nationalityURL := fmt.Sprintf("https://api.nationalize.io/?name=%s", person.Name)
-	var nationalizeRes NationalizeResponse
-	if err := fetchData(client, nationalityURL, &nationalizeRes); err != nil {
-		config.Log.Warnf("Не удалось обогатить национальность для %s: %v", person.Name, err)
-	} else if len(nationalizeRes.Country) > 0 {
-		// nationalize.io возвращает массив стран с вероятностями.
-		// Берем самую вероятную (первую в массиве, если API сортирует по убыванию вероятности, что обычно так).
-		person.Nationality = &nationalizeRes.Country[0].CountryID // Присваиваем указателю на string
-		config.Log.Debugf("Обогащена национальность для %s: %s", person.Name, *person.Nationality)
+	value, _ := result.(string)
+	return value, value != ""
+}
+
+// --- Вспомогательные функции для выполнения HTTP-запросов ---
+
+// fetchWithRetry выполняет до (1 + ENRICH_RETRIES) попыток fetchData, повторяя
+// только retryable-ответы (429 и 5xx) с экспоненциальным backoff и джиттером
+// между попытками. Любая другая ошибка или отмена контекста прерывает ретраи.
+func fetchWithRetry(ctx context.Context, client http.Client, url string, target interface{}) error {
+	var lastErr error
+	retries := enrichRetries()
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoffWithJitter(attempt - 1)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err := fetchData(ctx, client, url, target)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		var statusErr *httpStatusError
+		if !errors.As(err, &statusErr) || !statusErr.retryable() {
+			return err
+		}
+		config.Log.Debugf("Retryable error from %s (attempt %d/%d): %v", url, attempt+1, retries+1, err)
 	}
+	return lastErr
+}
 
-	config.Log.Debugf("Завершено обогащение данных для человека: %s", person.Name)
-	return nil // Возвращаем nil, если обогащение прошло без критических ошибок.
+// httpStatusError несёт статус-код неуспешного HTTP-ответа, чтобы
+// fetchWithRetry мог решить, стоит ли повторять запрос.
+type httpStatusError struct {
+	url        string
+	statusCode int
 }
 
-// --- Вспомогательная функция для выполнения HTTP-запросов и парсинга JSON ---
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("получен некорректный статус-код от %s: %d", e.url, e.statusCode)
+}
+
+func (e *httpStatusError) retryable() bool {
+	return e.statusCode == http.StatusTooManyRequests || e.statusCode >= 500
+}
 
 // fetchData выполняет GET-запрос к указанному URL и десериализует JSON-ответ
 // в предоставленную целевую структуру (target).
-func fetchData(client http.Client, url string, target interface{}) error {
+func fetchData(ctx context.Context, client http.Client, url string, target interface{}) error {
 	config.Log.Debugf("Выполнение HTTP-запроса к: %s", url)
-	resp, err := client.Get(url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка при создании запроса к %s: %w", url, err)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("ошибка при выполнении HTTP-запроса к %s: %w", url, err)
 	}
@@ -96,7 +262,7 @@ func fetchData(client http.Client, url string, target interface{}) error {
 
 	if resp.StatusCode != http.StatusOK {
 		// Если статус-код не 200 OK, значит, что-то пошло не так на стороне API.
-		return fmt.Errorf("получен некорректный статус-код от %s: %d", url, resp.StatusCode)
+		return &httpStatusError{url: url, statusCode: resp.StatusCode}
 	}
 
 	// Десериализуем (парсим) JSON-ответ в целевую структуру.
@@ -106,4 +272,4 @@ func fetchData(client http.Client, url string, target interface{}) error {
 
 	config.Log.Debugf("Успешный ответ от: %s", url)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchWithRetrySucceedsAfterRetryableFailure(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"age":30}`))
+	}))
+	defer server.Close()
+
+	var res AgifyResponse
+	err := fetchWithRetry(context.Background(), http.Client{}, server.URL, &res)
+	if err != nil {
+		t.Fatalf("expected fetchWithRetry to recover from a single 503, got: %v", err)
+	}
+	if res.Age != 30 {
+		t.Fatalf("expected age 30, got %d", res.Age)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected exactly 2 attempts, got %d", attempts)
+	}
+}
+
+func TestFetchWithRetryDoesNotRetryNonRetryableStatus(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var res AgifyResponse
+	err := fetchWithRetry(context.Background(), http.Client{}, server.URL, &res)
+	if err == nil {
+		t.Fatalf("expected an error for a non-retryable 400 response")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected no retries for a non-retryable status, got %d attempts", attempts)
+	}
+}
+
+func TestHTTPStatusErrorRetryable(t *testing.T) {
+	cases := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusBadRequest, false},
+		{http.StatusNotFound, false},
+	}
+	for _, tc := range cases {
+		e := &httpStatusError{statusCode: tc.status}
+		if got := e.retryable(); got != tc.want {
+			t.Errorf("retryable() for status %d = %v, want %v", tc.status, got, tc.want)
+		}
+	}
+}